@@ -0,0 +1,76 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jjfeiler/migrate/v4/database"
+)
+
+func TestLockTimeoutSeconds(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		want int
+	}{
+		{"unset blocks indefinitely", &Config{}, lockMaxWaitSeconds},
+		{"negative treated as unset", &Config{LockTimeout: -time.Second}, lockMaxWaitSeconds},
+		{"whole seconds", &Config{LockTimeout: 30 * time.Second}, 30},
+		{"sub-second truncates down", &Config{LockTimeout: 1500 * time.Millisecond}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lockTimeoutSeconds(tt.cfg); got != tt.want {
+				t.Errorf("lockTimeoutSeconds(%v) = %d, want %d", tt.cfg.LockTimeout, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockResultError(t *testing.T) {
+	query := []byte("BEGIN :result := DBMS_LOCK.REQUEST(...); END;")
+
+	if err := lockResultError(0, query); err != nil {
+		t.Errorf("lockResultError(0, ...) = %v, want nil", err)
+	}
+
+	if err := lockResultError(1, query); err == nil {
+		t.Error("lockResultError(1, ...) = nil, want ErrLockTimeout")
+	} else if dbErr, ok := err.(*database.Error); !ok || dbErr.OrigErr != ErrLockTimeout {
+		t.Errorf("lockResultError(1, ...) = %v, want wrapping ErrLockTimeout", err)
+	}
+
+	if err := lockResultError(2, query); err == nil {
+		t.Error("lockResultError(2, ...) = nil, want ErrLockDeadlock")
+	} else if dbErr, ok := err.(*database.Error); !ok || dbErr.OrigErr != ErrLockDeadlock {
+		t.Errorf("lockResultError(2, ...) = %v, want wrapping ErrLockDeadlock", err)
+	}
+
+	if err := lockResultError(99, query); err == nil {
+		t.Error("lockResultError(99, ...) = nil, want a generic error for an unrecognized code")
+	}
+}
+
+// TestLockModeValues pins the DBMS_LOCK.*_MODE numeric mapping threaded
+// into DBMS_LOCK.REQUEST's lockmode argument as int(Config.LockMode) -
+// getting one of these wrong silently requests the wrong lock mode.
+func TestLockModeValues(t *testing.T) {
+	tests := []struct {
+		mode LockMode
+		want int
+	}{
+		{LockModeNull, 1},
+		{LockModeRowShare, 2},
+		{LockModeRowExclusive, 3},
+		{LockModeShare, 4},
+		{LockModeShareRowExclusive, 5},
+		{LockModeExclusive, 6},
+	}
+
+	for _, tt := range tests {
+		if got := int(tt.mode); got != tt.want {
+			t.Errorf("int(%v) = %d, want %d", tt.mode, got, tt.want)
+		}
+	}
+}
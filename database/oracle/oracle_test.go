@@ -6,6 +6,7 @@ import (
 	sqldriver "database/sql/driver"
 	"io"
 	"log"
+	"strings"
 	"testing"
 	"github.com/jjfeiler/dktest"
 	"time"
@@ -120,3 +121,114 @@ func TestMigrate(t *testing.T) {
 		}
 	})
 }
+
+// TestMigrateUpDown migrates all the way up and back down to 0 using the
+// paired NNN_name.up.sql/NNN_name.down.sql fixtures, then repeats the trip
+// to prove Up/Down is repeatable and doesn't leave the database dirty.
+func TestMigrateUpDown(t *testing.T) {
+	dktesting.ParallelTest(t, specs, func(t *testing.T, c dktest.ContainerInfo) {
+		ip, port, err := c.Port(defaultPort)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		addr := oracleConnectionString(ip, port, "goracle", "goracle", "migratetest", false)
+		p := &GOracle{}
+		d, err := p.Open(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			if err := d.Close(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		m, err := migrate.NewWithDatabaseInstance("file://./examples/migrations", "migratetest", d)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 2; i++ {
+			if err := m.Up(); err != nil {
+				t.Fatal(err)
+			}
+			if version, dirty, err := m.Version(); err != nil {
+				t.Fatal(err)
+			} else if dirty {
+				t.Fatalf("expected clean database after Up(), got dirty at version %d", version)
+			}
+
+			if err := m.Down(); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := m.Version(); err != migrate.ErrNilVersion {
+				t.Fatalf("expected ErrNilVersion after Down(), got %v", err)
+			}
+		}
+	})
+}
+
+// TestMigrateExplicitSchema exercises the x-schema and x-tablespace URL
+// parameters end to end: it runs a real migration against the GORACLE
+// schema/USERS tablespace and verifies the objects actually land there,
+// rather than just checking that Config picked up the parsed values.
+func TestMigrateExplicitSchema(t *testing.T) {
+	dktesting.ParallelTest(t, specs, func(t *testing.T, c dktest.ContainerInfo) {
+		ip, port, err := c.Port(defaultPort)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		addr := oracleConnectionString(ip, port, "goracle", "goracle", "migratetest", false)
+		addr += "?x-schema=GORACLE&x-tablespace=USERS"
+
+		p := &GOracle{}
+		d, err := p.Open(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			if err := d.Close(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		if got := d.(*GOracle).config.SchemaName; got != "GORACLE" {
+			t.Fatalf("expected schema GORACLE, got %s", got)
+		}
+
+		m, err := migrate.NewWithDatabaseInstance("file://./examples/migrations", "migratetest", d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Up(); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			if err := d.(*GOracle).Drop(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		conn := d.(*GOracle).conn
+
+		var tableCount int
+		tableQuery := `SELECT COUNT(*) FROM ALL_TABLES WHERE OWNER = :1 AND TABLE_NAME = :2`
+		if err := conn.QueryRowContext(context.Background(), tableQuery, "GORACLE", "WIDGETS").Scan(&tableCount); err != nil {
+			t.Fatal(err)
+		}
+		if tableCount != 1 {
+			t.Fatalf("expected the widgets table to be created in schema GORACLE, found %d", tableCount)
+		}
+
+		var tablespace string
+		tablespaceQuery := `SELECT TABLESPACE_NAME FROM ALL_TABLES WHERE OWNER = :1 AND TABLE_NAME = :2`
+		if err := conn.QueryRowContext(context.Background(), tablespaceQuery, "GORACLE", strings.ToUpper(DefaultMigrationsTable)).Scan(&tablespace); err != nil {
+			t.Fatal(err)
+		}
+		if tablespace != "USERS" {
+			t.Fatalf("expected %s to live in tablespace USERS, got %s", DefaultMigrationsTable, tablespace)
+		}
+	})
+}
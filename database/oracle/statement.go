@@ -0,0 +1,337 @@
+package oracle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// statement is a single statement parsed out of a migration script, along
+// with the line on which it starts so that execution errors can point the
+// user back at the offending source.
+type statement struct {
+	text string
+	line int
+}
+
+// splitStatements splits a migration script into individual statements on
+// sep, skipping seps found inside single/double-quoted strings, Oracle
+// q'[...]' quoted strings, -- line comments, /* */ block comments and
+// BEGIN...END blocks. Lines consisting solely of "/" are always treated as a
+// statement terminator, matching the SQL*Plus convention used to run PL/SQL
+// blocks.
+func splitStatements(script string, sep string) ([]statement, error) {
+	if sep == "" {
+		sep = ";"
+	}
+
+	var stmts []statement
+	line := 1
+	startLine := 1
+	var buf strings.Builder
+
+	var (
+		inSingleQuote  bool
+		inDoubleQuote  bool
+		inQQuote       bool
+		qQuoteEnd      byte
+		inLineComment  bool
+		inBlockComment bool
+		plsqlDepth     int
+	)
+
+	flush := func() {
+		text := strings.TrimSpace(buf.String())
+		if text != "" {
+			stmts = append(stmts, statement{text: text, line: startLine})
+		}
+		buf.Reset()
+	}
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		var next rune
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		if c == '\n' {
+			line++
+		}
+
+		// A statement starts on the line of its first non-blank character.
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' && strings.TrimSpace(buf.String()) == "" {
+			startLine = line
+		}
+
+		if inLineComment {
+			buf.WriteRune(c)
+			if c == '\n' {
+				inLineComment = false
+			}
+			continue
+		}
+
+		if inBlockComment {
+			buf.WriteRune(c)
+			if c == '*' && next == '/' {
+				buf.WriteRune(next)
+				i++
+				inBlockComment = false
+			}
+			continue
+		}
+
+		if inQQuote {
+			buf.WriteRune(c)
+			if byte(c) == qQuoteEnd && next == '\'' {
+				buf.WriteRune(next)
+				i++
+				inQQuote = false
+			}
+			continue
+		}
+
+		if inSingleQuote {
+			buf.WriteRune(c)
+			if c == '\'' {
+				if next == '\'' {
+					buf.WriteRune(next)
+					i++
+				} else {
+					inSingleQuote = false
+				}
+			}
+			continue
+		}
+
+		if inDoubleQuote {
+			buf.WriteRune(c)
+			if c == '"' {
+				inDoubleQuote = false
+			}
+			continue
+		}
+
+		if c == '-' && next == '-' {
+			inLineComment = true
+			buf.WriteRune(c)
+			continue
+		}
+		if c == '/' && next == '*' {
+			inBlockComment = true
+			buf.WriteRune(c)
+			continue
+		}
+		if c == '\'' {
+			inSingleQuote = true
+			buf.WriteRune(c)
+			continue
+		}
+		if c == '"' {
+			inDoubleQuote = true
+			buf.WriteRune(c)
+			continue
+		}
+		if (c == 'q' || c == 'Q') && next == '\'' && i+2 < len(runes) {
+			if end, ok := qQuoteCloser(runes[i+2]); ok {
+				inQQuote = true
+				qQuoteEnd = end
+				buf.WriteRune(c)
+				buf.WriteRune(next)
+				buf.WriteRune(runes[i+2])
+				i += 2
+				continue
+			}
+		}
+
+		// A line containing only "/" ends a PL/SQL block, SQL*Plus style,
+		// independent of the configured separator.
+		if c == '/' && atLineStart(buf.String()) && onlySlashToEOL(runes, i) {
+			flush()
+			plsqlDepth = 0
+			startLine = line
+			continue
+		}
+
+		if plsqlDepth == 0 && strings.HasPrefix(string(runes[i:min(i+len(sep), len(runes))]), sep) {
+			buf.WriteString(sep)
+			i += len(sep) - 1
+			flush()
+			startLine = line
+			continue
+		}
+
+		if isWordChar(c) && isWordStart(buf.String()) {
+			word, wlen := readWord(runes[i:])
+			switch strings.ToUpper(word) {
+			case "BEGIN":
+				plsqlDepth++
+			case "CASE":
+				// The CASE of END CASE closes a block rather than opening one.
+				prev := strings.ToUpper(strings.TrimRight(buf.String(), " \t\r\n"))
+				if !strings.HasSuffix(prev, "END") || !isWordStart(prev[:len(prev)-3]) {
+					plsqlDepth++
+				}
+			case "END":
+				// END IF and END LOOP close constructs that never opened a block.
+				j := i + wlen
+				for j < len(runes) && strings.ContainsRune(" \t\r\n", runes[j]) {
+					j++
+				}
+				after, _ := readWord(runes[j:])
+				if after = strings.ToUpper(after); after != "IF" && after != "LOOP" && plsqlDepth > 0 {
+					plsqlDepth--
+				}
+			}
+			buf.WriteString(word)
+			i += wlen - 1
+			continue
+		}
+
+		buf.WriteRune(c)
+	}
+
+	flush()
+
+	if inSingleQuote || inDoubleQuote || inQQuote {
+		return nil, fmt.Errorf("unterminated quoted string starting before line %d", line)
+	}
+
+	return stmts, nil
+}
+
+// marker comments recognized inside migration scripts. Everything between a
+// StatementBegin/StatementEnd pair is taken verbatim as a single statement,
+// regardless of seps found inside it. This mirrors the annotation
+// rubenv/sql-migrate uses to protect PL/SQL blocks from being split.
+const (
+	statementBeginMarker = "-- +migrate StatementBegin"
+	statementEndMarker   = "-- +migrate StatementEnd"
+)
+
+// splitScript pulls out any StatementBegin/StatementEnd blocks first,
+// keeping their contents verbatim as single statements, then splits
+// whatever remains on sep via splitStatements.
+func splitScript(script string, sep string) ([]statement, error) {
+	lines := strings.Split(script, "\n")
+
+	var stmts []statement
+	var plain strings.Builder
+	plainStartLine := 1
+
+	flushPlain := func() error {
+		sub := plain.String()
+		plain.Reset()
+		if strings.TrimSpace(sub) == "" {
+			return nil
+		}
+		parsed, err := splitStatements(sub, sep)
+		if err != nil {
+			return err
+		}
+		for _, s := range parsed {
+			stmts = append(stmts, statement{text: s.text, line: plainStartLine + s.line - 1})
+		}
+		return nil
+	}
+
+	i := 0
+	for i < len(lines) {
+		if strings.TrimSpace(lines[i]) == statementBeginMarker {
+			if err := flushPlain(); err != nil {
+				return nil, err
+			}
+			blockStart := i + 1
+			var block []string
+			i++
+			for i < len(lines) && strings.TrimSpace(lines[i]) != statementEndMarker {
+				block = append(block, lines[i])
+				i++
+			}
+			if i >= len(lines) {
+				return nil, fmt.Errorf("unterminated %s starting at line %d", statementBeginMarker, blockStart)
+			}
+			text := strings.TrimSpace(strings.Join(block, "\n"))
+			if text != "" {
+				stmts = append(stmts, statement{text: text, line: blockStart})
+			}
+			i++ // skip the StatementEnd marker line
+			plainStartLine = i + 1
+			continue
+		}
+
+		plain.WriteString(lines[i])
+		plain.WriteString("\n")
+		i++
+	}
+
+	if err := flushPlain(); err != nil {
+		return nil, err
+	}
+
+	return stmts, nil
+}
+
+func qQuoteCloser(open rune) (byte, bool) {
+	switch open {
+	case '[':
+		return ']', true
+	case '(':
+		return ')', true
+	case '{':
+		return '}', true
+	case '<':
+		return '>', true
+	}
+	return 0, false
+}
+
+func atLineStart(bufSoFar string) bool {
+	trimmed := strings.TrimRight(bufSoFar, " \t")
+	return trimmed == "" || strings.HasSuffix(trimmed, "\n")
+}
+
+func onlySlashToEOL(runes []rune, slashIdx int) bool {
+	for i := slashIdx + 1; i < len(runes); i++ {
+		if runes[i] == '\n' {
+			return true
+		}
+		if runes[i] != ' ' && runes[i] != '\t' && runes[i] != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordStart(bufSoFar string) bool {
+	if bufSoFar == "" {
+		return true
+	}
+	last := bufSoFar[len(bufSoFar)-1]
+	return !(last == '_' || (last >= 'a' && last <= 'z') || (last >= 'A' && last <= 'Z') || (last >= '0' && last <= '9'))
+}
+
+func isWordChar(c rune) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func readWord(runes []rune) (string, int) {
+	n := 0
+	for n < len(runes) {
+		c := runes[n]
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			n++
+			continue
+		}
+		break
+	}
+	return string(runes[:n]), n
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,170 @@
+package oracle
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// splitStatementsWithTimeout guards against a regression of the parser
+// spinning forever on input it doesn't advance past.
+func splitStatementsWithTimeout(t *testing.T, script, sep string) []statement {
+	t.Helper()
+
+	type result struct {
+		stmts []statement
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		stmts, err := splitStatements(script, sep)
+		done <- result{stmts, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatalf("splitStatements(%q) error: %v", script, r.err)
+		}
+		return r.stmts
+	case <-time.After(2 * time.Second):
+		t.Fatalf("splitStatements(%q) did not return within 2s", script)
+		return nil
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		sep    string
+		want   []string
+	}{
+		{
+			name:   "parenthesized expression",
+			script: "SELECT (1) FROM dual;",
+			sep:    ";",
+			want:   []string{"SELECT (1) FROM dual;"},
+		},
+		{
+			name:   "multiple columns and statements",
+			script: "SELECT 1, 2 FROM dual; SELECT 3 FROM dual;",
+			sep:    ";",
+			want:   []string{"SELECT 1, 2 FROM dual;", "SELECT 3 FROM dual;"},
+		},
+		{
+			name:   "single quoted string containing separator",
+			script: "INSERT INTO t (a) VALUES ('a;b');",
+			sep:    ";",
+			want:   []string{"INSERT INTO t (a) VALUES ('a;b');"},
+		},
+		{
+			name:   "escaped single quote inside string",
+			script: "INSERT INTO t (a) VALUES ('it''s; fine');",
+			sep:    ";",
+			want:   []string{"INSERT INTO t (a) VALUES ('it''s; fine');"},
+		},
+		{
+			name:   "double quoted identifier containing separator",
+			script: `SELECT "a;b" FROM dual;`,
+			sep:    ";",
+			want:   []string{`SELECT "a;b" FROM dual;`},
+		},
+		{
+			name:   "q-quoted string containing separator",
+			script: "SELECT q'[a;b]' FROM dual;",
+			sep:    ";",
+			want:   []string{"SELECT q'[a;b]' FROM dual;"},
+		},
+		{
+			name:   "line comment containing separator",
+			script: "SELECT 1 FROM dual; -- comment; still a comment\nSELECT 2 FROM dual;",
+			sep:    ";",
+			want: []string{
+				"SELECT 1 FROM dual;",
+				"-- comment; still a comment\nSELECT 2 FROM dual;",
+			},
+		},
+		{
+			name:   "block comment containing separator",
+			script: "SELECT 1 FROM dual; /* comment; still comment */ SELECT 2 FROM dual;",
+			sep:    ";",
+			want: []string{
+				"SELECT 1 FROM dual;",
+				"/* comment; still comment */ SELECT 2 FROM dual;",
+			},
+		},
+		{
+			name:   "begin end block not split on inner separator",
+			script: "BEGIN\n  NULL;\n  NULL;\nEND;",
+			sep:    ";",
+			want:   []string{"BEGIN\n  NULL;\n  NULL;\nEND;"},
+		},
+		{
+			name:   "case end block not split on inner separator",
+			script: "SELECT CASE WHEN 1 = 1 THEN 'a;b' ELSE 'c' END FROM dual;",
+			sep:    ";",
+			want:   []string{"SELECT CASE WHEN 1 = 1 THEN 'a;b' ELSE 'c' END FROM dual;"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := splitStatementsWithTimeout(t, tt.script, tt.sep)
+			if len(stmts) != len(tt.want) {
+				t.Fatalf("got %d statements, want %d: %+v", len(stmts), len(tt.want), stmts)
+			}
+			for i, s := range stmts {
+				if s.text != tt.want[i] {
+					t.Errorf("statement %d = %q, want %q", i, s.text, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSplitStatementsNoHang(t *testing.T) {
+	// Inputs that previously caused splitStatements to spin forever because
+	// isWordStart only inspected the already-written buffer, not whether the
+	// current rune could itself start a word.
+	scripts := []string{
+		"SELECT (1) FROM dual;",
+		"SELECT 1, 2 FROM dual;",
+		"INSERT INTO t (a, b) VALUES (1, 2);",
+		"SELECT 1  FROM dual;", // double space
+		"(((1)));",
+	}
+	for _, script := range scripts {
+		splitStatementsWithTimeout(t, script, ";")
+	}
+}
+
+func TestSplitStatementsUnterminatedQuote(t *testing.T) {
+	_, err := splitStatements("SELECT 'unterminated FROM dual;", ";")
+	if err == nil {
+		t.Fatal("expected error for unterminated quoted string, got nil")
+	}
+}
+
+func TestSplitScriptStatementBeginEnd(t *testing.T) {
+	script := strings.Join([]string{
+		"CREATE TABLE t (a NUMBER);",
+		"-- +migrate StatementBegin",
+		"BEGIN",
+		"  EXECUTE IMMEDIATE 'DROP TABLE t';",
+		"END;",
+		"-- +migrate StatementEnd",
+		"SELECT 1 FROM dual;",
+	}, "\n")
+
+	stmts, err := splitScript(script, ";")
+	if err != nil {
+		t.Fatalf("splitScript error: %v", err)
+	}
+	if len(stmts) != 3 {
+		t.Fatalf("got %d statements, want 3: %+v", len(stmts), stmts)
+	}
+	if !strings.Contains(stmts[1].text, "EXECUTE IMMEDIATE") {
+		t.Errorf("statement 1 = %q, want StatementBegin block verbatim", stmts[1].text)
+	}
+}
@@ -0,0 +1,214 @@
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeStore is the durable state behind a fakeTxDriver connection: the
+// statements that have actually committed. ExecContext while a transaction
+// is open buffers into that transaction's pending list instead of writing
+// straight to committed, and only merges it in on Commit; Rollback discards
+// it. This lets TestRun* below assert the TransactionPerFile rollback and
+// atomicity behavior without a live Oracle connection.
+type fakeStore struct {
+	mu        sync.Mutex
+	committed []string
+	failOn    string
+}
+
+// fakeTxDriver is a minimal database/sql/driver.Driver backing fakeStores
+// keyed by DSN, so each test gets an isolated store by using its own DSN.
+type fakeTxDriver struct {
+	mu     sync.Mutex
+	stores map[string]*fakeStore
+}
+
+var fakeDriverInstance = &fakeTxDriver{}
+
+func init() {
+	sql.Register("fakeoracle-tx", fakeDriverInstance)
+}
+
+func (d *fakeTxDriver) storeFor(name string) *fakeStore {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stores == nil {
+		d.stores = map[string]*fakeStore{}
+	}
+	s, ok := d.stores[name]
+	if !ok {
+		s = &fakeStore{}
+		d.stores[name] = s
+	}
+	return s
+}
+
+func (d *fakeTxDriver) setFailOn(name, query string) {
+	d.storeFor(name).failOn = query
+}
+
+func (d *fakeTxDriver) committed(name string) []string {
+	s := d.storeFor(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, len(s.committed))
+	copy(out, s.committed)
+	return out
+}
+
+func (d *fakeTxDriver) Open(name string) (driver.Conn, error) {
+	return &fakeTxConn{store: d.storeFor(name)}, nil
+}
+
+type fakeTxConn struct {
+	store *fakeStore
+	tx    *fakeTx
+}
+
+func (c *fakeTxConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeTxStmt{conn: c, query: query}, nil
+}
+func (c *fakeTxConn) Close() error { return nil }
+func (c *fakeTxConn) Begin() (driver.Tx, error) {
+	tx := &fakeTx{conn: c}
+	c.tx = tx
+	return tx, nil
+}
+
+func (c *fakeTxConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.store.failOn != "" && query == c.store.failOn {
+		return nil, errors.New("fake exec error")
+	}
+	if c.tx != nil {
+		c.tx.pending = append(c.tx.pending, query)
+	} else {
+		c.store.mu.Lock()
+		c.store.committed = append(c.store.committed, query)
+		c.store.mu.Unlock()
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type fakeTxStmt struct {
+	conn  *fakeTxConn
+	query string
+}
+
+func (s *fakeTxStmt) Close() error  { return nil }
+func (s *fakeTxStmt) NumInput() int { return -1 }
+func (s *fakeTxStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, nil)
+}
+func (s *fakeTxStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("fakeTxStmt: Query not supported")
+}
+
+type fakeTx struct {
+	conn    *fakeTxConn
+	pending []string
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.conn.store.mu.Lock()
+	tx.conn.store.committed = append(tx.conn.store.committed, tx.pending...)
+	tx.conn.store.mu.Unlock()
+	tx.conn.tx = nil
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.conn.tx = nil
+	return nil
+}
+
+func newFakeGOracle(t *testing.T, dsn string) *GOracle {
+	t.Helper()
+
+	db, err := sql.Open("fakeoracle-tx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return &GOracle{
+		conn: conn,
+		db:   db,
+		config: &Config{
+			MigrationsTable:    DefaultMigrationsTable,
+			SchemaName:         "TESTSCHEMA",
+			StatementSeparator: ";",
+			TransactionMode:    TransactionPerFile,
+		},
+	}
+}
+
+// TestRunTransactionPerFileRollsBackOnFailure proves that a statement
+// failing partway through a TransactionPerFile migration rolls back
+// everything the migration itself did, and leaves g.tx unset so a later
+// SetVersion call can't mistake a dangling, half-applied transaction for
+// one left open by a successful Run.
+func TestRunTransactionPerFileRollsBackOnFailure(t *testing.T) {
+	dsn := "rollback"
+	fakeDriverInstance.setFailOn(dsn, "CREATE TABLE t2 (a NUMBER);")
+	g := newFakeGOracle(t, dsn)
+
+	script := "CREATE TABLE t1 (a NUMBER);\nCREATE TABLE t2 (a NUMBER);"
+	if err := g.Run(strings.NewReader(script)); err == nil {
+		t.Fatal("expected Run to fail on the second statement")
+	}
+
+	if g.tx != nil {
+		t.Fatal("expected g.tx to be nil after a failed Run, found a dangling transaction")
+	}
+	if got := fakeDriverInstance.committed(dsn); len(got) != 0 {
+		t.Fatalf("expected nothing committed after rollback, got %v", got)
+	}
+}
+
+// TestRunTransactionPerFileCommitsWithSetVersion proves that a successful
+// TransactionPerFile migration's statements stay uncommitted until
+// SetVersion commits the shared transaction, so the migration and the
+// version-table update land atomically.
+func TestRunTransactionPerFileCommitsWithSetVersion(t *testing.T) {
+	dsn := "commit"
+	g := newFakeGOracle(t, dsn)
+
+	script := "CREATE TABLE t1 (a NUMBER);\nCREATE TABLE t2 (a NUMBER);"
+	if err := g.Run(strings.NewReader(script)); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if g.tx == nil {
+		t.Fatal("expected Run to leave its transaction open for SetVersion")
+	}
+	if got := fakeDriverInstance.committed(dsn); len(got) != 0 {
+		t.Fatalf("expected migration statements to stay uncommitted before SetVersion, got %v", got)
+	}
+
+	if err := g.SetVersion(1, false); err != nil {
+		t.Fatalf("SetVersion failed: %v", err)
+	}
+
+	if g.tx != nil {
+		t.Fatal("expected SetVersion to clear g.tx after committing it")
+	}
+	got := fakeDriverInstance.committed(dsn)
+	if len(got) != 4 {
+		t.Fatalf("expected the 2 migration statements plus TRUNCATE+INSERT to commit together, got %v", got)
+	}
+	if got[0] != "CREATE TABLE t1 (a NUMBER);" || got[1] != "CREATE TABLE t2 (a NUMBER);" {
+		t.Fatalf("expected migration statements to commit first, got %v", got)
+	}
+}